@@ -1,21 +1,10 @@
 package cmdline
 
 import (
-	"fmt"
 	"github.com/Azure/draft/pkg/rpc"
-	"github.com/fatih/color"
 	"golang.org/x/net/context"
 	"os"
 	"sync"
-	"time"
-)
-
-var (
-	yellow = color.New(color.FgHiYellow, color.BgBlack, color.Bold).SprintFunc()
-	green  = color.New(color.FgHiGreen, color.BgBlack, color.Bold).SprintFunc()
-	blue   = color.New(color.FgHiBlue, color.BgBlack, color.Underline).SprintFunc()
-	cyan   = color.New(color.FgCyan, color.BgBlack).SprintFunc()
-	red    = color.New(color.FgHiRed, color.BgBlack).Add(color.Italic).SprintFunc()
 )
 
 // cmdline provides a basic cli ui/ux for draft client operations. It handles
@@ -69,12 +58,14 @@ func (cli *cmdline) Stop() error {
 
 // Display provides a UI for the draft client. When performing a draft 'up'
 // Display will output a measure of progress for each summary yielded by the
-// draft state machine.
+// draft state machine, using a Renderer selected by opts (WithFormat) or,
+// absent that, auto-selected from whether stdout is a terminal.
 func Display(ctx context.Context, app string, summaries <-chan *rpc.UpSummary, opts ...Option) {
 	var cli cmdline
-	cli.Init(ctx, WithStdout(os.Stdout))
+	cli.Init(ctx, append([]Option{WithStdout(os.Stdout)}, opts...)...)
+	renderer := cli.opts.renderer()
 
-	fmt.Fprintf(cli.opts.stdout, "%s: '%s'\n", blue("Draft Up Started"), cyan(app))
+	renderer.Started(app)
 	ongoing := make(map[string]chan rpc.UpSummary_StatusCode)
 	var wg sync.WaitGroup
 	defer func() {
@@ -90,12 +81,24 @@ func Display(ctx context.Context, app string, summaries <-chan *rpc.UpSummary, o
 			if !ok {
 				return
 			}
+			if summary.StageDesc == rpc.QueuedStageDesc {
+				renderer.Queued(app, int(summary.StatusCode))
+				continue
+			}
+			if summary.StageDesc == "" && summary.StatusCode == rpc.UpSummary_RETRYING {
+				// The connection dropped before any stage summary arrived,
+				// so there is no ongoing Stage to attach a "reconnecting"
+				// label to; render it directly instead of starting a
+				// phantom Stage keyed by "".
+				renderer.Reconnecting(app)
+				continue
+			}
 			if c, ok := ongoing[summary.StageDesc]; !ok {
 				c = make(chan rpc.UpSummary_StatusCode, 1)
 				ongoing[summary.StageDesc] = c
 				wg.Add(1)
 				go func(desc string, wg *sync.WaitGroup) {
-					progress(&cli, app, desc, c)
+					renderer.Stage(app, desc, c, cli.Done())
 					delete(ongoing, desc)
 					wg.Done()
 				}(summary.StageDesc, &wg)
@@ -107,50 +110,3 @@ func Display(ctx context.Context, app string, summaries <-chan *rpc.UpSummary, o
 		}
 	}
 }
-
-func progress(cli *cmdline, app, desc string, codes <-chan rpc.UpSummary_StatusCode) {
-	start := time.Now()
-	done := make(chan string, 1)
-	go func() {
-		defer close(done)
-		for {
-			select {
-			case code := <-codes:
-				switch code {
-				case rpc.UpSummary_SUCCESS:
-					done <- fmt.Sprintf("%s: %s  (%.4fs)\n", cyan(app), passStr(desc), time.Since(start).Seconds())
-					return
-				case rpc.UpSummary_FAILURE:
-					done <- fmt.Sprintf("%s: %s  (%.4fs)\n", cyan(app), failStr(desc), time.Since(start).Seconds())
-					return
-				}
-			case <-cli.Done():
-				return
-			}
-		}
-	}()
-	m := fmt.Sprintf("%s: %s", cyan(app), yellow(desc))
-	s := `-\|/-`
-	i := 0
-	for {
-		select {
-		case msg := <-done:
-			fmt.Fprintf(cli.opts.stdout, "\r%s", msg)
-			return
-		default:
-			fmt.Fprintf(cli.opts.stdout, "\r%s %c", m, s[i%len(s)])
-			time.Sleep(50 * time.Millisecond)
-			i++
-		}
-	}
-}
-
-func passStr(msg string) string {
-	const pass = "⚓"
-	return fmt.Sprintf("%s: %s", green(msg), pass)
-}
-
-func failStr(msg string) string {
-	const fail = "❌"
-	return fmt.Sprintf("%s: %s", red(msg), fail)
-}
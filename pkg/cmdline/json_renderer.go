@@ -0,0 +1,72 @@
+package cmdline
+
+import (
+	"encoding/json"
+	"io"
+	"sync"
+	"time"
+
+	"github.com/Azure/draft/pkg/rpc"
+)
+
+// jsonEvent is one line of NDJSON output emitted by jsonRenderer.
+type jsonEvent struct {
+	App       string `json:"app"`
+	Stage     string `json:"stage,omitempty"`
+	Status    string `json:"status"`
+	Position  int    `json:"position,omitempty"`
+	ElapsedMs int64  `json:"elapsed_ms,omitempty"`
+}
+
+// jsonRenderer renders progress as one JSON object per line per state
+// transition, for CI logs, editor integrations, and piping into other
+// tools. A mutex serializes writes since Display runs one Stage per build
+// stage concurrently.
+type jsonRenderer struct {
+	mu sync.Mutex
+	w  io.Writer
+}
+
+func newJSONRenderer(w io.Writer) *jsonRenderer {
+	return &jsonRenderer{w: w}
+}
+
+func (j *jsonRenderer) emit(e jsonEvent) {
+	j.mu.Lock()
+	defer j.mu.Unlock()
+	json.NewEncoder(j.w).Encode(e)
+}
+
+func (j *jsonRenderer) Started(app string) {
+	j.emit(jsonEvent{App: app, Status: "started"})
+}
+
+func (j *jsonRenderer) Queued(app string, position int) {
+	j.emit(jsonEvent{App: app, Status: "queued", Position: position})
+}
+
+func (j *jsonRenderer) Reconnecting(app string) {
+	j.emit(jsonEvent{App: app, Status: "retrying"})
+}
+
+func (j *jsonRenderer) Stage(app, stage string, codes <-chan rpc.UpSummary_StatusCode, stop <-chan struct{}) {
+	start := time.Now()
+	j.emit(jsonEvent{App: app, Stage: stage, Status: "running"})
+	for {
+		select {
+		case code := <-codes:
+			switch code {
+			case rpc.UpSummary_SUCCESS:
+				j.emit(jsonEvent{App: app, Stage: stage, Status: "success", ElapsedMs: time.Since(start).Nanoseconds() / int64(time.Millisecond)})
+				return
+			case rpc.UpSummary_FAILURE:
+				j.emit(jsonEvent{App: app, Stage: stage, Status: "failure", ElapsedMs: time.Since(start).Nanoseconds() / int64(time.Millisecond)})
+				return
+			case rpc.UpSummary_RETRYING:
+				j.emit(jsonEvent{App: app, Stage: stage, Status: "retrying", ElapsedMs: time.Since(start).Nanoseconds() / int64(time.Millisecond)})
+			}
+		case <-stop:
+			return
+		}
+	}
+}
@@ -0,0 +1,76 @@
+package cmdline
+
+import (
+	"io"
+	"os"
+)
+
+// options holds the settings used to configure a cmdline, populated by
+// applying a series of Option functions.
+type options struct {
+	stdout    io.Writer
+	format    Format
+	formatSet bool
+}
+
+// Option allows specifying various settings configurable on a cmdline.
+type Option func(*options)
+
+// DefaultOpts returns the Option applied before any caller-supplied Option,
+// filling in stdout if it was not otherwise set.
+func DefaultOpts() Option {
+	return func(o *options) {
+		if o.stdout == nil {
+			o.stdout = os.Stdout
+		}
+	}
+}
+
+// WithStdout sets the writer Display renders to.
+func WithStdout(w io.Writer) Option {
+	return func(o *options) {
+		o.stdout = w
+	}
+}
+
+// WithFormat selects the Renderer Display uses to report progress. If never
+// set, Display auto-selects FormatText when stdout is a terminal and
+// FormatJSON otherwise, so output stays readable in a shell but scriptable
+// in CI logs and editor integrations.
+func WithFormat(f Format) Option {
+	return func(o *options) {
+		o.format = f
+		o.formatSet = true
+	}
+}
+
+// renderer builds the Renderer selected by o, auto-detecting a format if
+// WithFormat was never applied.
+func (o options) renderer() Renderer {
+	format := o.format
+	if !o.formatSet {
+		format = FormatText
+		if !isTerminal(o.stdout) {
+			format = FormatJSON
+		}
+	}
+	switch format {
+	case FormatJSON:
+		return newJSONRenderer(o.stdout)
+	default:
+		return newTextRenderer(o.stdout)
+	}
+}
+
+// isTerminal reports whether w looks like an interactive terminal.
+func isTerminal(w io.Writer) bool {
+	f, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	fi, err := f.Stat()
+	if err != nil {
+		return false
+	}
+	return fi.Mode()&os.ModeCharDevice != 0
+}
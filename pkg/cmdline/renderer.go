@@ -0,0 +1,33 @@
+package cmdline
+
+import "github.com/Azure/draft/pkg/rpc"
+
+// Format selects which Renderer Display uses to report progress.
+type Format int
+
+const (
+	// FormatText renders a colored spinner per build stage, for interactive
+	// terminal use.
+	FormatText Format = iota
+	// FormatJSON renders one JSON object per line per state transition, for
+	// CI logs, editor integrations, and piping into other tools.
+	FormatJSON
+)
+
+// Renderer renders the progress of a draft 'up' session. Display drives a
+// Renderer instead of writing to stdout directly, so new formats (an
+// hclog-style key/value renderer, timestamped NDJSON, ...) can be added
+// without touching Display's select loop.
+type Renderer interface {
+	// Started is called once, when Display begins tracking app.
+	Started(app string)
+	// Queued reports that an Up RPC is waiting for a free slot on draftd.
+	Queued(app string, position int)
+	// Reconnecting reports that the client is retrying a transport error
+	// before any build stage has been observed yet, so there is no stage to
+	// attach the "reconnecting" indicator to.
+	Reconnecting(app string)
+	// Stage renders a single build stage from start to completion, blocking
+	// until codes reports a terminal status or stop is closed.
+	Stage(app, stage string, codes <-chan rpc.UpSummary_StatusCode, stop <-chan struct{})
+}
@@ -0,0 +1,99 @@
+package cmdline
+
+import (
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/Azure/draft/pkg/rpc"
+	"github.com/fatih/color"
+)
+
+var (
+	yellow = color.New(color.FgHiYellow, color.BgBlack, color.Bold).SprintFunc()
+	green  = color.New(color.FgHiGreen, color.BgBlack, color.Bold).SprintFunc()
+	blue   = color.New(color.FgHiBlue, color.BgBlack, color.Underline).SprintFunc()
+	cyan   = color.New(color.FgCyan, color.BgBlack).SprintFunc()
+	red    = color.New(color.FgHiRed, color.BgBlack).Add(color.Italic).SprintFunc()
+)
+
+// textRenderer renders progress as ANSI-colored spinner text, for
+// interactive terminal use.
+type textRenderer struct {
+	w io.Writer
+}
+
+func newTextRenderer(w io.Writer) *textRenderer {
+	return &textRenderer{w}
+}
+
+func (t *textRenderer) Started(app string) {
+	fmt.Fprintf(t.w, "%s: '%s'\n", blue("Draft Up Started"), cyan(app))
+}
+
+func (t *textRenderer) Queued(app string, position int) {
+	fmt.Fprintf(t.w, "\r%s: %s", cyan(app), yellow(queuedMsg(position)))
+}
+
+func (t *textRenderer) Reconnecting(app string) {
+	fmt.Fprintf(t.w, "\r%s: %s", cyan(app), yellow("reconnecting"))
+}
+
+func (t *textRenderer) Stage(app, stage string, codes <-chan rpc.UpSummary_StatusCode, stop <-chan struct{}) {
+	start := time.Now()
+	done := make(chan string, 1)
+	label := make(chan string, 1)
+	go func() {
+		defer close(done)
+		for {
+			select {
+			case code := <-codes:
+				switch code {
+				case rpc.UpSummary_SUCCESS:
+					done <- fmt.Sprintf("%s: %s  (%.4fs)\n", cyan(app), passStr(stage), time.Since(start).Seconds())
+					return
+				case rpc.UpSummary_FAILURE:
+					done <- fmt.Sprintf("%s: %s  (%.4fs)\n", cyan(app), failStr(stage), time.Since(start).Seconds())
+					return
+				case rpc.UpSummary_RETRYING:
+					label <- fmt.Sprintf("%s: %s", cyan(app), yellow(stage+" (reconnecting)"))
+				}
+			case <-stop:
+				return
+			}
+		}
+	}()
+	m := fmt.Sprintf("%s: %s", cyan(app), yellow(stage))
+	s := `-\|/-`
+	i := 0
+	for {
+		select {
+		case msg := <-done:
+			fmt.Fprintf(t.w, "\r%s", msg)
+			return
+		case l := <-label:
+			m = l
+		default:
+			fmt.Fprintf(t.w, "\r%s %c", m, s[i%len(s)])
+			time.Sleep(50 * time.Millisecond)
+			i++
+		}
+	}
+}
+
+func queuedMsg(position int) string {
+	if position == 0 {
+		return "queued (next)"
+	}
+	return fmt.Sprintf("queued (%d ahead)", position)
+}
+
+func passStr(msg string) string {
+	const pass = "⚓"
+	return fmt.Sprintf("%s: %s", green(msg), pass)
+}
+
+func failStr(msg string) string {
+	const fail = "❌"
+	return fmt.Sprintf("%s: %s", red(msg), fail)
+}
@@ -14,7 +14,7 @@ type clientImpl struct {
 }
 
 func newClientImpl(opts ...ClientOpt) Client {
-	var c clientImpl
+	c := clientImpl{opts: clientOpts{retry: defaultRetryPolicy}}
 	for _, opt := range opts {
 		opt(&c.opts)
 	}
@@ -40,11 +40,36 @@ func (c *clientImpl) Version(ctx context.Context) (*version.Version, error) {
 	return v, nil
 }
 
-// UpBuild implementes rpc.Client.UpBuild
+// UpBuild implementes rpc.Client.UpBuild. On a retryable transport error it
+// reconnects to draftd and replays req, surfacing an UpSummary_RETRYING
+// status on outc for each attempt beyond the first.
 func (c *clientImpl) UpBuild(ctx context.Context, req *UpRequest, outc chan<- *UpSummary) (err error) {
+	defer close(outc)
+	var stage string
+	for attempt := 0; ; attempt++ {
+		var s string
+		s, err = c.upBuildOnce(ctx, req, outc)
+		if s != "" {
+			stage = s
+		}
+		if err == nil || !isRetryable(err) || attempt >= c.opts.retry.maxAttempts-1 {
+			return err
+		}
+		outc <- &UpSummary{StageDesc: stage, StatusCode: UpSummary_RETRYING}
+		sleep(ctx, c.opts.retry.backoff(attempt))
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+	}
+}
+
+// upBuildOnce drives a single UpBuild attempt, forwarding every UpSummary to
+// outc. It returns the StageDesc of the last summary forwarded, so the
+// caller can report which stage a retry is reconnecting for.
+func (c *clientImpl) upBuildOnce(ctx context.Context, req *UpRequest, outc chan<- *UpSummary) (lastStage string, err error) {
 	conn, err := connect(c)
 	if err != nil {
-		return err
+		return "", err
 	}
 	defer conn.Close()
 
@@ -59,7 +84,6 @@ func (c *clientImpl) UpBuild(ctx context.Context, req *UpRequest, outc chan<- *U
 		}
 		close(errc)
 	}()
-	defer close(outc)
 	for msgc != nil || errc != nil {
 		select {
 		case msg, ok := <-msgc:
@@ -67,23 +91,54 @@ func (c *clientImpl) UpBuild(ctx context.Context, req *UpRequest, outc chan<- *U
 				msgc = nil
 				continue
 			}
-			outc <- msg.GetUpSummary()
+			summary := msg.GetUpSummary()
+			lastStage = summary.GetStageDesc()
+			outc <- summary
 		case err, ok := <-errc:
 			if !ok {
 				errc = nil
 				continue
 			}
-			return err
+			return lastStage, err
 		}
 	}
-	return nil
+	return lastStage, nil
 }
 
-// UpStream implementes rpc.Client.UpStream
+// UpStream implementes rpc.Client.UpStream. On a retryable transport error it
+// reconnects to draftd, replays the in-flight UpRequest (if any), and
+// surfaces an UpSummary_RETRYING status on outc for each attempt beyond the
+// first.
 func (c *clientImpl) UpStream(apictx context.Context, reqc <-chan *UpRequest, outc chan<- *UpSummary) error {
+	defer close(outc)
+	var pending *UpRequest
+	var stage string
+	for attempt := 0; ; attempt++ {
+		var err error
+		var s string
+		pending, s, err = c.upStreamOnce(apictx, reqc, pending, outc)
+		if s != "" {
+			stage = s
+		}
+		if err == nil || !isRetryable(err) || attempt >= c.opts.retry.maxAttempts-1 {
+			return err
+		}
+		outc <- &UpSummary{StageDesc: stage, StatusCode: UpSummary_RETRYING}
+		sleep(apictx, c.opts.retry.backoff(attempt))
+		if apictx.Err() != nil {
+			return apictx.Err()
+		}
+	}
+}
+
+// upStreamOnce drives a single stream attempt. If pending is non-nil it is
+// sent before resuming reads from reqc. It returns the UpRequest that was in
+// flight when the stream failed (for replay on the next attempt, or nil if
+// the stream ended cleanly) and the StageDesc of the last summary forwarded.
+func (c *clientImpl) upStreamOnce(apictx context.Context, reqc <-chan *UpRequest, pending *UpRequest, outc chan<- *UpSummary) (inFlight *UpRequest, lastStage string, err error) {
 	conn, err := connect(c)
 	if err != nil {
-		return err
+		return pending, "", err
 	}
 	defer conn.Close()
 
@@ -91,21 +146,25 @@ func (c *clientImpl) UpStream(apictx context.Context, reqc <-chan *UpRequest, ou
 	rpcctx := context.Background()
 
 	msgc := make(chan *UpMessage)
+	done := make(chan struct{})
 	go func() {
+		defer close(done)
 		for msg := range msgc {
 			if summary := msg.GetUpSummary(); summary != nil {
+				lastStage = summary.GetStageDesc()
 				outc <- summary
 			}
 		}
-		close(outc)
 	}()
-	return up_stream(rpcctx, client, reqc, msgc)
+	inFlight, err = up_stream(rpcctx, client, reqc, pending, msgc)
+	<-done
+	return inFlight, lastStage, err
 }
 
 func up_build(ctx context.Context, rpc DraftClient, msg *UpRequest, outc chan<- *UpMessage) error {
 	s, err := rpc.UpBuild(ctx, &UpMessage{&UpMessage_UpRequest{msg}})
 	if err != nil {
-		return fmt.Errorf("rpc error handling up_build: %v", err)
+		return fmt.Errorf("rpc error handling up_build: %w", err)
 	}
 	defer close(outc)
 	for {
@@ -114,16 +173,19 @@ func up_build(ctx context.Context, rpc DraftClient, msg *UpRequest, outc chan<-
 			return nil
 		}
 		if err != nil {
-			return fmt.Errorf("rpc error handling up_build recv: %v", err)
+			return fmt.Errorf("rpc error handling up_build recv: %w", err)
 		}
 		outc <- resp
 	}
 }
 
-func up_stream(ctx context.Context, rpc DraftClient, send <-chan *UpRequest, recv chan<- *UpMessage) error {
+// up_stream drives a single bidirectional stream attempt, sending pending
+// first (if set) before resuming reads from send. It returns the UpRequest
+// that was in flight when the stream failed, so the caller can replay it.
+func up_stream(ctx context.Context, rpc DraftClient, send <-chan *UpRequest, pending *UpRequest, recv chan<- *UpMessage) (*UpRequest, error) {
 	stream, err := rpc.UpStream(ctx)
 	if err != nil {
-		return fmt.Errorf("rpc error handling up_stream: %v", err)
+		return pending, fmt.Errorf("rpc error handling up_stream: %w", err)
 	}
 	done := make(chan struct{})
 	errc := make(chan error)
@@ -141,31 +203,47 @@ func up_stream(ctx context.Context, rpc DraftClient, send <-chan *UpRequest, rec
 				return
 			}
 			if err != nil {
-				errc <- fmt.Errorf("failed to receive a summary: %v", err)
+				errc <- fmt.Errorf("failed to receive a summary: %w", err)
 				return
 			}
 			recv <- m
 		}
 	}()
+
+	if pending != nil {
+		if err := stream.Send(&UpMessage{&UpMessage_UpRequest{pending}}); err != nil {
+			return pending, fmt.Errorf("failed to send an up message: %w", err)
+		}
+		pending = nil
+	}
 	for {
 		select {
 		case msg, ok := <-send:
 			if !ok {
-				return nil
+				return nil, nil
 			}
 			req := &UpMessage{&UpMessage_UpRequest{msg}}
 			if err := stream.Send(req); err != nil {
-				return fmt.Errorf("failed to send an up message: %v", err)
+				return msg, fmt.Errorf("failed to send an up message: %w", err)
 			}
 		case err := <-errc:
-			return err
+			return nil, err
 		}
 	}
 }
 
 // connect connects the DraftClient to the DraftServer.
 func connect(c *clientImpl, opts ...grpc.DialOption) (conn *grpc.ClientConn, err error) {
-	if conn, err = grpc.Dial(c.opts.addr, grpc.WithInsecure()); err != nil {
+	creds, err := c.opts.tls.transportCredentials()
+	if err != nil {
+		return nil, fmt.Errorf("failed to set up TLS for %q: %v", c.opts.addr, err)
+	}
+	if creds != nil {
+		opts = append(opts, grpc.WithTransportCredentials(creds))
+	} else {
+		opts = append(opts, grpc.WithInsecure())
+	}
+	if conn, err = grpc.Dial(c.opts.addr, opts...); err != nil {
 		return nil, fmt.Errorf("failed to dial %q: %v", c.opts.addr, err)
 	}
 	return conn, nil
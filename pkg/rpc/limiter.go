@@ -0,0 +1,148 @@
+package rpc
+
+import (
+	"sync"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc"
+)
+
+// Up RPCs are the only methods bounded by a Limiter; all other methods pass
+// through the interceptor untouched.
+const (
+	upBuildMethod  = "/draft.Draft/UpBuild"
+	upStreamMethod = "/draft.Draft/UpStream"
+)
+
+// QueuedStageDesc is the UpSummary.StageDesc a Limiter reports while an Up
+// RPC is waiting for a free slot; UpSummary.StatusCode holds the caller's
+// 0-indexed queue position for these summaries.
+const QueuedStageDesc = "queued"
+
+// Limiter bounds how many Up RPCs draftd actually executes concurrently,
+// queuing the rest. Waiters are served FIFO.
+type Limiter struct {
+	maxProcs int
+
+	mu      sync.Mutex
+	active  int
+	waiters []*waiter
+}
+
+type waiter struct {
+	ch       chan struct{}
+	onQueued func(position int)
+	reported int // last position passed to onQueued; -1 before the first
+}
+
+// NewLimiter returns a Limiter that allows at most maxProcs Up RPCs to
+// execute at once. maxProcs <= 0 means unbounded.
+func NewLimiter(maxProcs int) *Limiter {
+	return &Limiter{maxProcs: maxProcs}
+}
+
+// Acquire blocks until a slot is free, reserves it, and returns a release
+// func the caller must invoke when done executing. While queued, onQueued is
+// invoked with this caller's 0-indexed position every time it changes (0
+// means it is next in line); onQueued is never called once a slot is held.
+func (l *Limiter) Acquire(ctx context.Context, onQueued func(position int)) (release func(), err error) {
+	l.mu.Lock()
+	if l.maxProcs <= 0 || l.active < l.maxProcs {
+		l.active++
+		l.mu.Unlock()
+		return l.newRelease(), nil
+	}
+	w := &waiter{ch: make(chan struct{}), onQueued: onQueued, reported: -1}
+	l.waiters = append(l.waiters, w)
+	notify := l.queuePositionsLocked()
+	l.mu.Unlock()
+	notify()
+
+	select {
+	case <-w.ch:
+		return l.newRelease(), nil
+	case <-ctx.Done():
+		l.abandon(w)
+		return nil, ctx.Err()
+	}
+}
+
+func (l *Limiter) newRelease() func() {
+	var once sync.Once
+	return func() {
+		once.Do(l.release)
+	}
+}
+
+func (l *Limiter) release() {
+	l.mu.Lock()
+	if len(l.waiters) == 0 {
+		l.active--
+		l.mu.Unlock()
+		return
+	}
+	next := l.waiters[0]
+	l.waiters = l.waiters[1:]
+	notify := l.queuePositionsLocked()
+	l.mu.Unlock()
+	close(next.ch)
+	notify()
+}
+
+func (l *Limiter) abandon(w *waiter) {
+	l.mu.Lock()
+	for i, other := range l.waiters {
+		if other == w {
+			l.waiters = append(l.waiters[:i], l.waiters[i+1:]...)
+			break
+		}
+	}
+	notify := l.queuePositionsLocked()
+	l.mu.Unlock()
+	notify()
+}
+
+// queuePositionsLocked must be called with l.mu held. It snapshots the
+// waiters whose position differs from the one last reported to them, so
+// their onQueued callbacks can be invoked after l.mu is released, since those
+// callbacks may block on a network send (e.g. streamInterceptor's
+// ss.SendMsg) and must not do so while holding the lock. Waiters whose
+// position is unchanged are not notified again.
+func (l *Limiter) queuePositionsLocked() func() {
+	var changed []*waiter
+	var positions []int
+	for i, w := range l.waiters {
+		if w.onQueued != nil && w.reported != i {
+			w.reported = i
+			changed = append(changed, w)
+			positions = append(positions, i)
+		}
+	}
+	return func() {
+		for i, w := range changed {
+			w.onQueued(positions[i])
+		}
+	}
+}
+
+// streamInterceptor bounds concurrent execution of the Up RPCs, queuing the
+// rest and reporting their position back to the caller as a "queued"
+// UpSummary before the RPC handler is invoked.
+func (l *Limiter) streamInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if info.FullMethod != upBuildMethod && info.FullMethod != upStreamMethod {
+			return handler(srv, ss)
+		}
+		release, err := l.Acquire(ss.Context(), func(position int) {
+			ss.SendMsg(&UpMessage{&UpMessage_UpSummary{&UpSummary{
+				StageDesc:  QueuedStageDesc,
+				StatusCode: UpSummary_StatusCode(position),
+			}}})
+		})
+		if err != nil {
+			return err
+		}
+		defer release()
+		return handler(srv, ss)
+	}
+}
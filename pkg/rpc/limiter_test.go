@@ -0,0 +1,163 @@
+package rpc
+
+import (
+	"sync"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+)
+
+func TestLimiterUnboundedNeverBlocks(t *testing.T) {
+	l := NewLimiter(0)
+	for i := 0; i < 10; i++ {
+		release, err := l.Acquire(context.Background(), nil)
+		if err != nil {
+			t.Fatalf("unexpected error: %v", err)
+		}
+		release()
+	}
+}
+
+func TestLimiterBoundsConcurrency(t *testing.T) {
+	const maxProcs = 3
+	l := NewLimiter(maxProcs)
+
+	var mu sync.Mutex
+	active, peak := 0, 0
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			release, err := l.Acquire(context.Background(), nil)
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			mu.Lock()
+			active++
+			if active > peak {
+				peak = active
+			}
+			mu.Unlock()
+
+			time.Sleep(time.Millisecond)
+
+			mu.Lock()
+			active--
+			mu.Unlock()
+			release()
+		}()
+	}
+	wg.Wait()
+
+	if peak > maxProcs {
+		t.Fatalf("observed %d concurrently executing, want at most %d", peak, maxProcs)
+	}
+}
+
+func TestLimiterQueuePositionsAreFIFO(t *testing.T) {
+	l := NewLimiter(1)
+	release, err := l.Acquire(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	const waiters = 3
+	var mu sync.Mutex
+	positions := make([][]int, waiters)
+	releases := make(chan func(), waiters)
+	enqueued := make([]chan struct{}, waiters)
+	for i := range enqueued {
+		enqueued[i] = make(chan struct{}, 1)
+	}
+	for i := 0; i < waiters; i++ {
+		i := i
+		go func() {
+			r, err := l.Acquire(context.Background(), func(position int) {
+				mu.Lock()
+				positions[i] = append(positions[i], position)
+				mu.Unlock()
+				select {
+				case enqueued[i] <- struct{}{}:
+				default:
+				}
+			})
+			if err != nil {
+				t.Error(err)
+				return
+			}
+			releases <- r
+		}()
+		<-enqueued[i] // enqueue this waiter before starting the next, so queue order is deterministic
+	}
+
+	release()
+	for i := 0; i < waiters; i++ {
+		(<-releases)()
+	}
+
+	for i, p := range positions {
+		if len(p) == 0 || p[0] != i {
+			t.Errorf("waiter %d: initial reported position = %v, want first element %d", i, p, i)
+		}
+	}
+}
+
+func TestLimiterAcquireCancelledContextAbandonsWaiter(t *testing.T) {
+	l := NewLimiter(1)
+	release, err := l.Acquire(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if _, err := l.Acquire(ctx, nil); err == nil {
+		t.Fatal("expected Acquire to return an error for an already-cancelled context")
+	}
+
+	l.mu.Lock()
+	waiting := len(l.waiters)
+	l.mu.Unlock()
+	if waiting != 0 {
+		t.Fatalf("expected the cancelled waiter to be removed from the queue, got %d still waiting", waiting)
+	}
+	release()
+}
+
+// TestLimiterSlowOnQueuedDoesNotBlockOtherAcquires guards against the
+// regression fixed in queuePositionsLocked: onQueued callbacks (which, via
+// streamInterceptor, can block on a network send to a stalled client) must
+// not be invoked while Limiter.mu is held, or one stalled client can freeze
+// admission control for everyone else.
+func TestLimiterSlowOnQueuedDoesNotBlockOtherAcquires(t *testing.T) {
+	l := NewLimiter(1)
+	release, err := l.Acquire(context.Background(), nil)
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	defer release()
+
+	queued := make(chan struct{})
+	unblock := make(chan struct{})
+	go func() {
+		l.Acquire(context.Background(), func(position int) {
+			close(queued)
+			<-unblock // simulates a stalled client whose SendMsg never returns
+		})
+	}()
+	<-queued
+
+	ctx, cancel := context.WithTimeout(context.Background(), 200*time.Millisecond)
+	defer cancel()
+	start := time.Now()
+	if _, err := l.Acquire(ctx, nil); err == nil {
+		t.Fatal("expected the context deadline to be exceeded while queued")
+	}
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("Acquire took %v, appears to have blocked on Limiter.mu held by the slow onQueued callback", elapsed)
+	}
+	close(unblock)
+}
@@ -0,0 +1,81 @@
+package rpc
+
+import (
+	k8s "k8s.io/client-go/kubernetes"
+)
+
+// clientOpts holds the options used to configure a Client, populated by
+// applying a series of ClientOpt functions.
+type clientOpts struct {
+	addr  string
+	tls   tlsConfig
+	retry retryPolicy
+}
+
+// tlsConfig captures the transport credentials a Client dials draftd with.
+// When insecure is true, all other fields are ignored and the connection is
+// made in plaintext.
+type tlsConfig struct {
+	insecure   bool
+	caPEM      []byte
+	certPEM    []byte
+	keyPEM     []byte
+	serverName string
+	err        error
+}
+
+// ClientOpt allows specifying various settings configurable by the client.
+type ClientOpt func(*clientOpts)
+
+// WithServerAddr specifies the remote address of draftd.
+func WithServerAddr(addr string) ClientOpt {
+	return func(opts *clientOpts) {
+		opts.addr = addr
+	}
+}
+
+// WithInsecure opts the client out of TLS entirely, dialing draftd in
+// plaintext. This is only intended for local development against a draftd
+// that was itself started without TLS.
+func WithInsecure() ClientOpt {
+	return func(opts *clientOpts) {
+		opts.tls.insecure = true
+	}
+}
+
+// WithTLSServerName overrides the server name used to verify the hostname
+// returned by draftd's certificate. It is useful when addr is an IP or a
+// port-forwarded address that does not match the certificate's CN/SAN.
+func WithTLSServerName(serverName string) ClientOpt {
+	return func(opts *clientOpts) {
+		opts.tls.serverName = serverName
+	}
+}
+
+// WithTLSFromFile configures mutual TLS using PEM-encoded files on disk: caFile
+// is the CA bundle used to verify draftd's certificate, certFile/keyFile are
+// the client's own certificate and key presented to draftd. certFile and
+// keyFile may be empty if draftd does not require client certificates.
+func WithTLSFromFile(caFile, certFile, keyFile string) ClientOpt {
+	return func(opts *clientOpts) {
+		ca, cert, key, err := loadTLSFiles(caFile, certFile, keyFile)
+		opts.tls.caPEM, opts.tls.certPEM, opts.tls.keyPEM = ca, cert, key
+		if err != nil {
+			opts.tls.err = err
+		}
+	}
+}
+
+// WithTLSFromSecret configures mutual TLS using a Kubernetes Secret, reusing
+// the caller's kube client. The secret is expected to hold the same keys as
+// a core/v1 TLS secret (ca.crt, tls.crt, tls.key), with tls.crt/tls.key
+// optional if draftd does not require client certificates.
+func WithTLSFromSecret(client k8s.Interface, namespace, name string) ClientOpt {
+	return func(opts *clientOpts) {
+		ca, cert, key, err := loadTLSSecret(client, namespace, name)
+		opts.tls.caPEM, opts.tls.certPEM, opts.tls.keyPEM = ca, cert, key
+		if err != nil {
+			opts.tls.err = err
+		}
+	}
+}
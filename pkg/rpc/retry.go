@@ -0,0 +1,85 @@
+package rpc
+
+import (
+	"errors"
+	"math/rand"
+	"time"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// UpSummary_RETRYING reports that the client is reconnecting to draftd after
+// a transport error and will replay the in-flight request once it succeeds.
+// Its value must match UpSummary.StatusCode.RETRYING in draft.proto, the
+// authoritative source for this enum; do not change one without the other.
+const UpSummary_RETRYING UpSummary_StatusCode = 2
+
+// retryPolicy controls how UpBuild and UpStream reconnect after a transport
+// error: maxAttempts total tries (including the first), with exponential
+// backoff between initialBackoff and maxBackoff, randomized by jitter (a
+// fraction of the computed backoff, e.g. 0.2 for +/-20%).
+type retryPolicy struct {
+	maxAttempts    int
+	initialBackoff time.Duration
+	maxBackoff     time.Duration
+	jitter         float64
+}
+
+var defaultRetryPolicy = retryPolicy{
+	maxAttempts:    5,
+	initialBackoff: 500 * time.Millisecond,
+	maxBackoff:     30 * time.Second,
+	jitter:         0.2,
+}
+
+// WithRetryPolicy overrides the default retry policy used to reconnect
+// UpBuild/UpStream after a transport error.
+func WithRetryPolicy(maxAttempts int, initialBackoff, maxBackoff time.Duration, jitter float64) ClientOpt {
+	return func(opts *clientOpts) {
+		opts.retry = retryPolicy{maxAttempts, initialBackoff, maxBackoff, jitter}
+	}
+}
+
+// backoff returns the delay to wait before retry attempt n (0-indexed).
+func (r retryPolicy) backoff(n int) time.Duration {
+	d := r.initialBackoff << uint(n)
+	if d <= 0 || d > r.maxBackoff {
+		d = r.maxBackoff
+	}
+	if r.jitter <= 0 {
+		return d
+	}
+	delta := float64(d) * r.jitter
+	return d - time.Duration(delta) + time.Duration(rand.Float64()*2*delta)
+}
+
+// sleep waits for d, returning early if ctx is cancelled.
+func sleep(ctx context.Context, d time.Duration) {
+	t := time.NewTimer(d)
+	defer t.Stop()
+	select {
+	case <-t.C:
+	case <-ctx.Done():
+	}
+}
+
+// isRetryable reports whether err represents a transient transport failure
+// worth reconnecting for, such as a dropped connection or a draftd rollout.
+// Callers wrap the underlying gRPC error with fmt.Errorf("...: %w", err), so
+// isRetryable walks the chain with errors.Unwrap rather than trusting
+// status.Code on the outermost error, which would always report Unknown.
+func isRetryable(err error) bool {
+	for e := err; e != nil; e = errors.Unwrap(e) {
+		s, ok := status.FromError(e)
+		if !ok {
+			continue
+		}
+		switch s.Code() {
+		case codes.Unavailable, codes.DeadlineExceeded, codes.Aborted, codes.ResourceExhausted:
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,77 @@
+package rpc
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"golang.org/x/net/context"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestRetryPolicyBackoff(t *testing.T) {
+	policy := retryPolicy{initialBackoff: time.Second, maxBackoff: 10 * time.Second}
+	tests := []struct {
+		attempt int
+		want    time.Duration
+	}{
+		{0, time.Second},
+		{1, 2 * time.Second},
+		{2, 4 * time.Second},
+		{3, 8 * time.Second},
+		{4, 10 * time.Second}, // would be 16s uncapped, clamped to maxBackoff
+		{63, 10 * time.Second}, // large shift overflows to a non-positive duration, also clamped
+	}
+	for _, tt := range tests {
+		if got := policy.backoff(tt.attempt); got != tt.want {
+			t.Errorf("backoff(%d) = %v, want %v", tt.attempt, got, tt.want)
+		}
+	}
+}
+
+func TestRetryPolicyBackoffJitter(t *testing.T) {
+	policy := retryPolicy{initialBackoff: time.Second, maxBackoff: time.Minute, jitter: 0.2}
+	d := policy.backoff(0)
+	min, max := 800*time.Millisecond, 1200*time.Millisecond
+	if d < min || d > max {
+		t.Fatalf("backoff(0) = %v, want within [%v, %v]", d, min, max)
+	}
+}
+
+func TestSleepReturnsEarlyOnCancel(t *testing.T) {
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	start := time.Now()
+	sleep(ctx, time.Minute)
+	if elapsed := time.Since(start); elapsed > time.Second {
+		t.Fatalf("sleep did not return early on a cancelled context, took %v", elapsed)
+	}
+}
+
+func TestIsRetryable(t *testing.T) {
+	tests := []struct {
+		name string
+		err  error
+		want bool
+	}{
+		{"nil", nil, false},
+		{"plain error", errors.New("boom"), false},
+		{"unavailable", status.Error(codes.Unavailable, "down"), true},
+		{"deadline exceeded", status.Error(codes.DeadlineExceeded, "slow"), true},
+		{"aborted", status.Error(codes.Aborted, "conflict"), true},
+		{"resource exhausted", status.Error(codes.ResourceExhausted, "busy"), true},
+		{"invalid argument", status.Error(codes.InvalidArgument, "bad"), false},
+		{"wrapped retryable", fmt.Errorf("rpc error handling up_build: %w", status.Error(codes.Unavailable, "down")), true},
+		{"wrapped non-retryable", fmt.Errorf("rpc error handling up_build: %w", status.Error(codes.InvalidArgument, "bad")), false},
+		{"%v-wrapped retryable is invisible", fmt.Errorf("rpc error handling up_build: %v", status.Error(codes.Unavailable, "down")), false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isRetryable(tt.err); got != tt.want {
+				t.Errorf("isRetryable(%v) = %v, want %v", tt.err, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,23 @@
+package rpc
+
+import (
+	"github.com/Azure/draft/pkg/version"
+	"golang.org/x/net/context"
+)
+
+// Client is the interface that wraps the draft client API, used by draft
+// commands to communicate with draftd over gRPC.
+type Client interface {
+	// Version fetches the version of the connected draftd server.
+	Version(ctx context.Context) (*version.Version, error)
+	// UpBuild performs a single, non-interactive build/deploy cycle.
+	UpBuild(ctx context.Context, req *UpRequest, outc chan<- *UpSummary) error
+	// UpStream performs a long-lived, bidirectional build/deploy cycle driven
+	// by the caller sending UpRequests on reqc.
+	UpStream(ctx context.Context, reqc <-chan *UpRequest, outc chan<- *UpSummary) error
+}
+
+// NewClient creates a new Client configured with the given ClientOpts.
+func NewClient(opts ...ClientOpt) Client {
+	return newClientImpl(opts...)
+}
@@ -0,0 +1,126 @@
+package rpc
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	k8s "k8s.io/client-go/kubernetes"
+)
+
+// serverOpts holds the options used to configure the draftd gRPC server,
+// populated by applying a series of ServerOpt functions.
+type serverOpts struct {
+	tls     serverTLSConfig
+	limiter *Limiter
+}
+
+// serverTLSConfig captures the transport credentials draftd serves with. When
+// insecure is true, all other fields are ignored and draftd serves plaintext.
+type serverTLSConfig struct {
+	insecure    bool
+	certPEM     []byte
+	keyPEM      []byte
+	clientCAPEM []byte
+	requireMTLS bool
+	err         error
+}
+
+// ServerOpt allows specifying various settings configurable on the draftd
+// gRPC server.
+type ServerOpt func(*serverOpts)
+
+// WithServerInsecure opts draftd out of TLS entirely, serving in plaintext.
+func WithServerInsecure() ServerOpt {
+	return func(opts *serverOpts) {
+		opts.tls.insecure = true
+	}
+}
+
+// WithServerTLSFromFile configures draftd to serve TLS using the PEM-encoded
+// certificate/key at certFile/keyFile. If clientCAFile is non-empty, draftd
+// requires and verifies client certificates signed by that CA bundle.
+func WithServerTLSFromFile(certFile, keyFile, clientCAFile string) ServerOpt {
+	return func(opts *serverOpts) {
+		cert, key, err := loadKeyPairFile(certFile, keyFile)
+		opts.tls.certPEM, opts.tls.keyPEM = cert, key
+		if err != nil {
+			opts.tls.err = err
+			return
+		}
+		if clientCAFile == "" {
+			return
+		}
+		ca, err := loadCAFile(clientCAFile)
+		opts.tls.clientCAPEM = ca
+		opts.tls.requireMTLS = true
+		if err != nil {
+			opts.tls.err = err
+		}
+	}
+}
+
+// WithServerTLSFromSecret configures draftd to serve TLS using a Kubernetes
+// Secret named name in namespace, reusing the caller's kube client. The
+// secret is expected to hold the same keys as a core/v1 TLS secret (tls.crt,
+// tls.key), with ca.crt optional; if present, draftd requires and verifies
+// client certificates signed by that CA bundle. This is the realistic
+// deployment path for draftd, which runs in-cluster against a
+// cert-manager-managed (or similarly mounted) serving certificate.
+func WithServerTLSFromSecret(client k8s.Interface, namespace, name string) ServerOpt {
+	return func(opts *serverOpts) {
+		cert, key, ca, err := loadServerTLSSecret(client, namespace, name)
+		opts.tls.certPEM, opts.tls.keyPEM, opts.tls.clientCAPEM = cert, key, ca
+		if err != nil {
+			opts.tls.err = err
+			return
+		}
+		opts.tls.requireMTLS = len(ca) > 0
+	}
+}
+
+// WithMaxProcs bounds how many Up RPCs draftd will execute concurrently;
+// additional calls are queued FIFO and report their queue position back to
+// the caller. maxProcs <= 0 means unbounded (the default).
+func WithMaxProcs(maxProcs int) ServerOpt {
+	return func(opts *serverOpts) {
+		opts.limiter = NewLimiter(maxProcs)
+	}
+}
+
+// NewServer creates a *grpc.Server configured with the given ServerOpts.
+func NewServer(opts ...ServerOpt) (*grpc.Server, error) {
+	var o serverOpts
+	for _, opt := range opts {
+		opt(&o)
+	}
+	if o.tls.err != nil && !o.tls.insecure {
+		return nil, fmt.Errorf("failed to set up TLS: %v", o.tls.err)
+	}
+
+	var grpcOpts []grpc.ServerOption
+	if o.limiter != nil {
+		grpcOpts = append(grpcOpts, grpc.StreamInterceptor(o.limiter.streamInterceptor()))
+	}
+	if o.tls.insecure {
+		return grpc.NewServer(grpcOpts...), nil
+	}
+
+	cert, err := tls.X509KeyPair(o.tls.certPEM, o.tls.keyPEM)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse server certificate/key: %v", err)
+	}
+	cfg := &tls.Config{Certificates: []tls.Certificate{cert}}
+	if o.tls.requireMTLS {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(o.tls.clientCAPEM) {
+			return nil, fmt.Errorf("failed to parse client CA bundle")
+		}
+		cfg.ClientCAs = pool
+		cfg.ClientAuth = tls.RequireAndVerifyClientCert
+	}
+	grpcOpts = append(grpcOpts, grpc.Creds(credentials.NewTLS(cfg)))
+	return grpc.NewServer(grpcOpts...), nil
+}
@@ -0,0 +1,122 @@
+package rpc
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+
+	"google.golang.org/grpc/credentials"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8s "k8s.io/client-go/kubernetes"
+)
+
+// these mirror the conventional keys of a core/v1 "kubernetes.io/tls" Secret,
+// plus "ca.crt" for the CA bundle used to verify the peer.
+const (
+	secretKeyCA   = "ca.crt"
+	secretKeyCert = "tls.crt"
+	secretKeyKey  = "tls.key"
+)
+
+// transportCredentials builds the credentials.TransportCredentials describing
+// how a client should authenticate draftd and present its own identity, based
+// on tls. It returns nil credentials when t.insecure is set.
+func (t tlsConfig) transportCredentials() (credentials.TransportCredentials, error) {
+	if t.insecure {
+		return nil, nil
+	}
+	if t.err != nil {
+		return nil, t.err
+	}
+
+	cfg := &tls.Config{ServerName: t.serverName}
+	if len(t.caPEM) > 0 {
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(t.caPEM) {
+			return nil, fmt.Errorf("failed to parse CA bundle")
+		}
+		cfg.RootCAs = pool
+	}
+	if len(t.certPEM) > 0 && len(t.keyPEM) > 0 {
+		cert, err := tls.X509KeyPair(t.certPEM, t.keyPEM)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse client certificate/key: %v", err)
+		}
+		cfg.Certificates = []tls.Certificate{cert}
+	}
+	return credentials.NewTLS(cfg), nil
+}
+
+// loadTLSFiles reads the PEM contents of a CA bundle and an optional client
+// certificate/key pair from disk. certFile and keyFile may both be empty.
+func loadTLSFiles(caFile, certFile, keyFile string) (ca, cert, key []byte, err error) {
+	if ca, err = ioutil.ReadFile(caFile); err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to read CA file %q: %v", caFile, err)
+	}
+	if certFile == "" && keyFile == "" {
+		return ca, nil, nil, nil
+	}
+	if cert, err = ioutil.ReadFile(certFile); err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to read client cert file %q: %v", certFile, err)
+	}
+	if key, err = ioutil.ReadFile(keyFile); err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to read client key file %q: %v", keyFile, err)
+	}
+	return ca, cert, key, nil
+}
+
+// loadCAFile reads the PEM contents of a single CA bundle from disk.
+func loadCAFile(caFile string) ([]byte, error) {
+	ca, err := ioutil.ReadFile(caFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CA file %q: %v", caFile, err)
+	}
+	return ca, nil
+}
+
+// loadKeyPairFile reads the PEM contents of a certificate/key pair from disk.
+func loadKeyPairFile(certFile, keyFile string) (cert, key []byte, err error) {
+	if cert, err = ioutil.ReadFile(certFile); err != nil {
+		return nil, nil, fmt.Errorf("failed to read cert file %q: %v", certFile, err)
+	}
+	if key, err = ioutil.ReadFile(keyFile); err != nil {
+		return nil, nil, fmt.Errorf("failed to read key file %q: %v", keyFile, err)
+	}
+	return cert, key, nil
+}
+
+// loadTLSSecret reads the same PEM contents as loadTLSFiles from a
+// Kubernetes Secret named name in namespace.
+func loadTLSSecret(client k8s.Interface, namespace, name string) (ca, cert, key []byte, err error) {
+	secret, err := client.CoreV1().Secrets(namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to fetch TLS secret %q: %v", name, err)
+	}
+	ca, ok := secret.Data[secretKeyCA]
+	if !ok {
+		return nil, nil, nil, fmt.Errorf("secret %q missing key %q", name, secretKeyCA)
+	}
+	return ca, secret.Data[secretKeyCert], secret.Data[secretKeyKey], nil
+}
+
+// loadServerTLSSecret reads the same PEM contents as loadKeyPairFile/
+// loadCAFile from a Kubernetes Secret named name in namespace: tls.crt/
+// tls.key are required (draftd's own serving certificate, e.g. managed by
+// cert-manager), ca.crt is optional and present only when the secret also
+// carries the CA bundle used to verify client certificates.
+func loadServerTLSSecret(client k8s.Interface, namespace, name string) (cert, key, ca []byte, err error) {
+	secret, err := client.CoreV1().Secrets(namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("failed to fetch TLS secret %q: %v", name, err)
+	}
+	cert, ok := secret.Data[secretKeyCert]
+	if !ok {
+		return nil, nil, nil, fmt.Errorf("secret %q missing key %q", name, secretKeyCert)
+	}
+	key, ok = secret.Data[secretKeyKey]
+	if !ok {
+		return nil, nil, nil, fmt.Errorf("secret %q missing key %q", name, secretKeyKey)
+	}
+	return cert, key, secret.Data[secretKeyCA], nil
+}
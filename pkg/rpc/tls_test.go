@@ -0,0 +1,133 @@
+package rpc
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"testing"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+	v1 "k8s.io/client-go/pkg/api/v1"
+)
+
+// selfSignedCertPEM generates a throwaway self-signed certificate/key pair,
+// usable both as a CA bundle and as a TLS key pair.
+func selfSignedCertPEM(t *testing.T) (certPEM, keyPEM []byte) {
+	t.Helper()
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatalf("failed to generate test key: %v", err)
+	}
+	tmpl := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "draft-test"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+		IsCA:         true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, tmpl, tmpl, &key.PublicKey, key)
+	if err != nil {
+		t.Fatalf("failed to create test certificate: %v", err)
+	}
+	certPEM = pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+	keyPEM = pem.EncodeToMemory(&pem.Block{Type: "RSA PRIVATE KEY", Bytes: x509.MarshalPKCS1PrivateKey(key)})
+	return certPEM, keyPEM
+}
+
+func TestTransportCredentialsInsecure(t *testing.T) {
+	creds, err := tlsConfig{insecure: true, err: errors.New("should be ignored")}.transportCredentials()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if creds != nil {
+		t.Fatalf("expected nil credentials for an insecure config, got %v", creds)
+	}
+}
+
+func TestTransportCredentialsPropagatesLoadError(t *testing.T) {
+	want := errors.New("boom")
+	if _, err := (tlsConfig{err: want}).transportCredentials(); err != want {
+		t.Fatalf("expected load error to propagate, got %v", err)
+	}
+}
+
+func TestTransportCredentialsBadCAPEM(t *testing.T) {
+	if _, err := (tlsConfig{caPEM: []byte("not a cert")}).transportCredentials(); err == nil {
+		t.Fatal("expected an error for an unparseable CA bundle")
+	}
+}
+
+func TestTransportCredentialsBadCertKeyPair(t *testing.T) {
+	if _, err := (tlsConfig{certPEM: []byte("not a cert"), keyPEM: []byte("not a key")}).transportCredentials(); err == nil {
+		t.Fatal("expected an error for an unparseable certificate/key pair")
+	}
+}
+
+func TestTransportCredentialsValidMutualTLS(t *testing.T) {
+	certPEM, keyPEM := selfSignedCertPEM(t)
+	creds, err := (tlsConfig{caPEM: certPEM, certPEM: certPEM, keyPEM: keyPEM, serverName: "draftd"}).transportCredentials()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if creds == nil {
+		t.Fatal("expected non-nil credentials")
+	}
+}
+
+func tlsSecret(name string, data map[string][]byte) *v1.Secret {
+	return &v1.Secret{ObjectMeta: metav1.ObjectMeta{Name: name, Namespace: "default"}, Data: data}
+}
+
+func TestLoadTLSSecret(t *testing.T) {
+	client := fake.NewSimpleClientset(tlsSecret("draftd-tls", map[string][]byte{
+		secretKeyCA:   []byte("ca"),
+		secretKeyCert: []byte("cert"),
+		secretKeyKey:  []byte("key"),
+	}))
+	ca, cert, key, err := loadTLSSecret(client, "default", "draftd-tls")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(ca) != "ca" || string(cert) != "cert" || string(key) != "key" {
+		t.Fatalf("unexpected contents: ca=%q cert=%q key=%q", ca, cert, key)
+	}
+}
+
+func TestLoadTLSSecretMissingCA(t *testing.T) {
+	client := fake.NewSimpleClientset(tlsSecret("draftd-tls", map[string][]byte{}))
+	if _, _, _, err := loadTLSSecret(client, "default", "draftd-tls"); err == nil {
+		t.Fatal("expected an error for a secret missing ca.crt")
+	}
+}
+
+func TestLoadServerTLSSecret(t *testing.T) {
+	client := fake.NewSimpleClientset(tlsSecret("draftd-serving", map[string][]byte{
+		secretKeyCert: []byte("cert"),
+		secretKeyKey:  []byte("key"),
+	}))
+	cert, key, ca, err := loadServerTLSSecret(client, "default", "draftd-serving")
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if string(cert) != "cert" || string(key) != "key" || ca != nil {
+		t.Fatalf("unexpected contents: cert=%q key=%q ca=%q", cert, key, ca)
+	}
+}
+
+func TestLoadServerTLSSecretMissingCertOrKey(t *testing.T) {
+	for name, data := range map[string]map[string][]byte{
+		"missing cert": {secretKeyKey: []byte("key")},
+		"missing key":  {secretKeyCert: []byte("cert")},
+	} {
+		client := fake.NewSimpleClientset(tlsSecret("draftd-serving", data))
+		if _, _, _, err := loadServerTLSSecret(client, "default", "draftd-serving"); err == nil {
+			t.Errorf("%s: expected an error", name)
+		}
+	}
+}
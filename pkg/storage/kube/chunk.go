@@ -0,0 +1,113 @@
+package kube
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/Azure/draft/pkg/storage"
+)
+
+const (
+	chunkMarkerPrefix = "draft-chunked:"
+	// maxInlineSize bounds how large an encoded build payload can be before
+	// CreateBuild splits it across chunk-sized child resources instead of
+	// storing it inline in the per-app aggregate ConfigMap/Secret. It is kept
+	// well under etcd's 1 MiB object limit to leave room for other builds
+	// sharing the same aggregate object.
+	maxInlineSize = 200 * 1024
+	chunkSize     = 200 * 1024
+)
+
+// chunkBackend is implemented by each storage driver to create, fetch and
+// delete the child resources used to hold build payloads too large to store
+// inline in the per-app aggregate ConfigMap/Secret.
+type chunkBackend interface {
+	createChunk(name string, labels map[string]string, content string) error
+	getChunk(name string) (string, error)
+	deleteChunk(name string) error
+}
+
+// chunkName returns the name of the n'th chunk resource for a build.
+func chunkName(appName, buildID string, n int) string {
+	return fmt.Sprintf("%s-%s-%d", appName, buildID, n)
+}
+
+// encodeValue gob/base64-encodes build and, if the result is too large to
+// store inline, splits it across chunk child resources created via backend.
+// It returns the value that should be stored under build.BuildID in the
+// per-app aggregate ConfigMap/Secret.
+func encodeValue(backend chunkBackend, appName string, build *storage.Object) (string, error) {
+	content, err := storage.EncodeToString(build)
+	if err != nil {
+		return "", err
+	}
+	if len(content) <= maxInlineSize {
+		return content, nil
+	}
+	pieces := splitChunks(content, chunkSize)
+	labels := map[string]string{"heritage": "draft", "appname": appName, "buildID": build.BuildID}
+	for n, piece := range pieces {
+		if err := backend.createChunk(chunkName(appName, build.BuildID, n), labels, piece); err != nil {
+			return "", fmt.Errorf("failed to write chunk %d for build %q: %v", n, build.BuildID, err)
+		}
+	}
+	return fmt.Sprintf("%s%d", chunkMarkerPrefix, len(pieces)), nil
+}
+
+// decodeValue reverses encodeValue, reassembling chunked payloads by
+// fetching each child resource via backend.
+func decodeValue(backend chunkBackend, appName, buildID, value string) (*storage.Object, error) {
+	n, ok := parseChunkMarker(value)
+	if !ok {
+		return storage.DecodeString(value)
+	}
+	var buf strings.Builder
+	for i := 0; i < n; i++ {
+		piece, err := backend.getChunk(chunkName(appName, buildID, i))
+		if err != nil {
+			return nil, fmt.Errorf("failed to read chunk %d for build %q: %v", i, buildID, err)
+		}
+		buf.WriteString(piece)
+	}
+	return storage.DecodeString(buf.String())
+}
+
+// deleteChunks deletes every chunk child resource for a build. It is a no-op
+// if value does not indicate the build was chunked.
+func deleteChunks(backend chunkBackend, appName, buildID, value string) error {
+	n, ok := parseChunkMarker(value)
+	if !ok {
+		return nil
+	}
+	for i := 0; i < n; i++ {
+		if err := backend.deleteChunk(chunkName(appName, buildID, i)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func parseChunkMarker(value string) (int, bool) {
+	if !strings.HasPrefix(value, chunkMarkerPrefix) {
+		return 0, false
+	}
+	n, err := strconv.Atoi(strings.TrimPrefix(value, chunkMarkerPrefix))
+	if err != nil {
+		return 0, false
+	}
+	return n, true
+}
+
+func splitChunks(content string, size int) []string {
+	var chunks []string
+	for len(content) > 0 {
+		n := size
+		if n > len(content) {
+			n = len(content)
+		}
+		chunks = append(chunks, content[:n])
+		content = content[n:]
+	}
+	return chunks
+}
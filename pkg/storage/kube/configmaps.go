@@ -0,0 +1,253 @@
+package kube
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Azure/draft/pkg/storage"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8s "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/pkg/api/v1"
+)
+
+// ConfigMapsDriverName is the driver name used to select this backend with
+// NewStore.
+const ConfigMapsDriverName = "configmaps"
+
+// ConfigMapsStore represents a Kubernetes ConfigMap storage engine for a
+// storage.Object, with one aggregate ConfigMap per application and, for
+// oversized builds, one additional ConfigMap per chunk.
+type ConfigMapsStore struct {
+	client    k8s.Interface
+	namespace string
+	opts      storeOpts
+}
+
+var _ storage.Store = (*ConfigMapsStore)(nil)
+var _ chunkBackend = (*ConfigMapsStore)(nil)
+
+// NewConfigMapsStore returns a Store that persists builds as ConfigMaps.
+func NewConfigMapsStore(c k8s.Interface, namespace string, opts ...StoreOpt) *ConfigMapsStore {
+	s := &ConfigMapsStore{client: c, namespace: namespace, opts: defaultStoreOpts()}
+	for _, opt := range opts {
+		opt(&s.opts)
+	}
+	return s
+}
+
+// DeleteBuilds deletes all draft builds for the application specified by appName.
+func (s *ConfigMapsStore) DeleteBuilds(ctx context.Context, appName string) ([]*storage.Object, error) {
+	cfgmap, err := s.client.CoreV1().ConfigMaps(s.namespace).Get(appName, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	builds, err := s.decodeAll(appName, cfgmap)
+	if err != nil {
+		return nil, err
+	}
+	for buildID, value := range cfgmap.Data {
+		if err := deleteChunks(s, appName, buildID, value); err != nil {
+			return nil, err
+		}
+	}
+	err = s.client.CoreV1().ConfigMaps(s.namespace).Delete(appName, &metav1.DeleteOptions{})
+	return builds, err
+}
+
+// DeleteBuild deletes the draft build given by buildID for the application specified by appName.
+func (s *ConfigMapsStore) DeleteBuild(ctx context.Context, appName, buildID string) (obj *storage.Object, err error) {
+	cfgmap, err := s.client.CoreV1().ConfigMaps(s.namespace).Get(appName, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	value, ok := cfgmap.Data[buildID]
+	if !ok {
+		return nil, fmt.Errorf("application %q storage object %q not found", appName, buildID)
+	}
+	if obj, err = decodeValue(s, appName, buildID, value); err != nil {
+		return nil, err
+	}
+	if err := deleteChunks(s, appName, buildID, value); err != nil {
+		return nil, err
+	}
+	delete(cfgmap.Data, buildID)
+	_, err = s.client.CoreV1().ConfigMaps(s.namespace).Update(cfgmap)
+	return obj, err
+}
+
+// CreateBuild stores a draft.Build for the application specified by appName.
+// Oversized payloads are split across chunk ConfigMaps rather than stored
+// inline. An existing aggregate ConfigMap is updated in place; only a new
+// application creates one. If adding build would push the aggregate
+// ConfigMap over its soft cap, older builds are pruned first per the
+// Store's RetentionPolicy.
+func (s *ConfigMapsStore) CreateBuild(ctx context.Context, appName string, build *storage.Object) error {
+	value, err := encodeValue(s, appName, build)
+	if err != nil {
+		return err
+	}
+
+	cfgmap, err := s.client.CoreV1().ConfigMaps(s.namespace).Get(appName, metav1.GetOptions{})
+	isNew := apierrors.IsNotFound(err)
+	if err != nil && !isNew {
+		return err
+	}
+	if isNew {
+		cfgmap = &v1.ConfigMap{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   appName,
+				Labels: map[string]string{"heritage": "draft", "appname": appName},
+			},
+			Data: map[string]string{},
+		}
+	}
+	if cfgmap.Data == nil {
+		cfgmap.Data = map[string]string{}
+	}
+	cfgmap.Data[build.BuildID] = value
+
+	if configMapDataSize(cfgmap.Data) > s.opts.softCapBytes {
+		if err := s.pruneConfigMap(appName, cfgmap); err != nil {
+			return err
+		}
+	}
+
+	if isNew {
+		_, err = s.client.CoreV1().ConfigMaps(s.namespace).Create(cfgmap)
+	} else {
+		_, err = s.client.CoreV1().ConfigMaps(s.namespace).Update(cfgmap)
+	}
+	return err
+}
+
+// PruneBuilds deletes the builds for appName that policy selects for
+// removal.
+func (s *ConfigMapsStore) PruneBuilds(ctx context.Context, appName string, policy storage.RetentionPolicy) ([]*storage.Object, error) {
+	cfgmap, err := s.client.CoreV1().ConfigMaps(s.namespace).Get(appName, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	builds, err := s.decodeAll(appName, cfgmap)
+	if err != nil {
+		return nil, err
+	}
+	pruned, err := s.removeBuilds(appName, cfgmap, builds, buildsToPrune(builds, policy))
+	if err != nil || len(pruned) == 0 {
+		return pruned, err
+	}
+	_, err = s.client.CoreV1().ConfigMaps(s.namespace).Update(cfgmap)
+	return pruned, err
+}
+
+// pruneConfigMap prunes cfgmap in place per the Store's configured
+// RetentionPolicy, without persisting the change; the caller is expected to
+// Create/Update cfgmap itself. If the policy alone doesn't bring cfgmap.Data
+// back under softCapBytes, additional oldest-first builds are pruned until
+// it does, so a generous MaxCount/MaxAge cannot keep it over the soft cap.
+// Builds protected by the policy's KeepSuccessful/KeepFailed are never
+// pruned, so cfgmap.Data can stay over softCapBytes if only those remain.
+func (s *ConfigMapsStore) pruneConfigMap(appName string, cfgmap *v1.ConfigMap) error {
+	builds, err := s.decodeAll(appName, cfgmap)
+	if err != nil {
+		return err
+	}
+	removed, err := s.removeBuilds(appName, cfgmap, builds, buildsToPrune(builds, s.opts.prunePolicy))
+	if err != nil {
+		return err
+	}
+	if s.opts.softCapBytes <= 0 {
+		return nil
+	}
+	return pruneForCap(remainingBuilds(builds, removed), s.opts.prunePolicy, func() bool {
+		return configMapDataSize(cfgmap.Data) <= s.opts.softCapBytes
+	}, func(b *storage.Object) error {
+		if err := deleteChunks(s, appName, b.BuildID, cfgmap.Data[b.BuildID]); err != nil {
+			return err
+		}
+		delete(cfgmap.Data, b.BuildID)
+		return nil
+	})
+}
+
+// removeBuilds deletes the chunk resources for, and removes from cfgmap.Data,
+// every build in builds whose BuildID is in losers.
+func (s *ConfigMapsStore) removeBuilds(appName string, cfgmap *v1.ConfigMap, builds []*storage.Object, losers []string) ([]*storage.Object, error) {
+	if len(losers) == 0 {
+		return nil, nil
+	}
+	loserSet := make(map[string]bool, len(losers))
+	for _, id := range losers {
+		loserSet[id] = true
+	}
+	var removed []*storage.Object
+	for _, b := range builds {
+		if !loserSet[b.BuildID] {
+			continue
+		}
+		if err := deleteChunks(s, appName, b.BuildID, cfgmap.Data[b.BuildID]); err != nil {
+			return nil, err
+		}
+		delete(cfgmap.Data, b.BuildID)
+		removed = append(removed, b)
+	}
+	return removed, nil
+}
+
+// GetBuilds returns a slice of builds for the given app name.
+func (s *ConfigMapsStore) GetBuilds(ctx context.Context, appName string) ([]*storage.Object, error) {
+	cfgmap, err := s.client.CoreV1().ConfigMaps(s.namespace).Get(appName, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return s.decodeAll(appName, cfgmap)
+}
+
+// GetBuild returns the build associated with buildID for the specified app name.
+func (s *ConfigMapsStore) GetBuild(ctx context.Context, appName, buildID string) (*storage.Object, error) {
+	cfgmap, err := s.client.CoreV1().ConfigMaps(s.namespace).Get(appName, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	value, ok := cfgmap.Data[buildID]
+	if !ok {
+		return nil, fmt.Errorf("application %q storage object %q not found", appName, buildID)
+	}
+	return decodeValue(s, appName, buildID, value)
+}
+
+func (s *ConfigMapsStore) decodeAll(appName string, cfgmap *v1.ConfigMap) ([]*storage.Object, error) {
+	var builds []*storage.Object
+	for buildID, value := range cfgmap.Data {
+		build, err := decodeValue(s, appName, buildID, value)
+		if err != nil {
+			return nil, err
+		}
+		builds = append(builds, build)
+	}
+	return builds, nil
+}
+
+// createChunk implements chunkBackend.
+func (s *ConfigMapsStore) createChunk(name string, labels map[string]string, content string) error {
+	cm := &v1.ConfigMap{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+		Data:       map[string]string{"chunk": content},
+	}
+	_, err := s.client.CoreV1().ConfigMaps(s.namespace).Create(cm)
+	return err
+}
+
+// getChunk implements chunkBackend.
+func (s *ConfigMapsStore) getChunk(name string) (string, error) {
+	cm, err := s.client.CoreV1().ConfigMaps(s.namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+	return cm.Data["chunk"], nil
+}
+
+// deleteChunk implements chunkBackend.
+func (s *ConfigMapsStore) deleteChunk(name string) error {
+	return s.client.CoreV1().ConfigMaps(s.namespace).Delete(name, &metav1.DeleteOptions{})
+}
@@ -0,0 +1,42 @@
+package kube
+
+import "github.com/Azure/draft/pkg/storage"
+
+// defaultSoftCapBytes bounds how large an aggregate ConfigMap/Secret's Data
+// is allowed to grow before CreateBuild prunes old builds, kept well under
+// etcd's 1 MiB object limit to leave headroom for the build being created.
+const defaultSoftCapBytes = 750 * 1024
+
+// defaultPrunePolicy is applied by CreateBuild when an aggregate object
+// would otherwise exceed its soft cap, unless overridden with WithPrunePolicy.
+var defaultPrunePolicy = storage.RetentionPolicy{MaxCount: 50}
+
+// storeOpts holds the options shared by ConfigMapsStore and SecretsStore,
+// populated by applying a series of StoreOpt functions.
+type storeOpts struct {
+	softCapBytes int
+	prunePolicy  storage.RetentionPolicy
+}
+
+func defaultStoreOpts() storeOpts {
+	return storeOpts{softCapBytes: defaultSoftCapBytes, prunePolicy: defaultPrunePolicy}
+}
+
+// StoreOpt allows specifying various settings configurable on a Store.
+type StoreOpt func(*storeOpts)
+
+// WithSoftCap overrides the aggregate ConfigMap/Secret size, in bytes, at
+// which CreateBuild prunes old builds before writing a new one.
+func WithSoftCap(bytes int) StoreOpt {
+	return func(o *storeOpts) {
+		o.softCapBytes = bytes
+	}
+}
+
+// WithPrunePolicy overrides the RetentionPolicy CreateBuild applies when an
+// aggregate ConfigMap/Secret would otherwise exceed its soft cap.
+func WithPrunePolicy(policy storage.RetentionPolicy) StoreOpt {
+	return func(o *storeOpts) {
+		o.prunePolicy = policy
+	}
+}
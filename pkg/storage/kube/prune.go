@@ -0,0 +1,101 @@
+package kube
+
+import (
+	"sort"
+	"time"
+
+	"github.com/Azure/draft/pkg/storage"
+)
+
+// buildsToPrune returns the BuildIDs that policy selects for removal from
+// the full set of recorded builds for an application, keeping the most
+// recently created builds first.
+func buildsToPrune(builds []*storage.Object, policy storage.RetentionPolicy) []string {
+	sorted := make([]*storage.Object, len(builds))
+	copy(sorted, builds)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].CreatedAt.After(sorted[j].CreatedAt)
+	})
+
+	now := time.Now()
+	var losers []string
+	for i, b := range sorted {
+		if policy.KeepSuccessful && b.Success {
+			continue
+		}
+		if policy.KeepFailed && !b.Success {
+			continue
+		}
+		tooMany := policy.MaxCount > 0 && i >= policy.MaxCount
+		tooOld := policy.MaxAge > 0 && now.Sub(b.CreatedAt) > policy.MaxAge
+		if tooMany || tooOld {
+			losers = append(losers, b.BuildID)
+		}
+	}
+	return losers
+}
+
+// remainingBuilds returns the subset of all not present in removed.
+func remainingBuilds(all, removed []*storage.Object) []*storage.Object {
+	removedSet := make(map[string]bool, len(removed))
+	for _, b := range removed {
+		removedSet[b.BuildID] = true
+	}
+	var remaining []*storage.Object
+	for _, b := range all {
+		if !removedSet[b.BuildID] {
+			remaining = append(remaining, b)
+		}
+	}
+	return remaining
+}
+
+// pruneForCap removes builds from remaining, oldest first and respecting
+// policy's KeepSuccessful/KeepFailed selectors, until underCap reports the
+// aggregate object is back under its soft cap. It stops once underCap is
+// satisfied or every remaining build is protected, so a generous
+// MaxCount/MaxAge (or the zero policy) can never leave the aggregate object
+// over the soft cap this step exists to enforce.
+func pruneForCap(remaining []*storage.Object, policy storage.RetentionPolicy, underCap func() bool, remove func(*storage.Object) error) error {
+	if underCap() {
+		return nil
+	}
+	sorted := make([]*storage.Object, len(remaining))
+	copy(sorted, remaining)
+	sort.Slice(sorted, func(i, j int) bool {
+		return sorted[i].CreatedAt.Before(sorted[j].CreatedAt)
+	})
+	for _, b := range sorted {
+		if policy.KeepSuccessful && b.Success {
+			continue
+		}
+		if policy.KeepFailed && !b.Success {
+			continue
+		}
+		if err := remove(b); err != nil {
+			return err
+		}
+		if underCap() {
+			return nil
+		}
+	}
+	return nil
+}
+
+// configMapDataSize estimates the encoded size of a ConfigMap's Data.
+func configMapDataSize(data map[string]string) int {
+	n := 0
+	for k, v := range data {
+		n += len(k) + len(v)
+	}
+	return n
+}
+
+// secretDataSize estimates the encoded size of a Secret's Data.
+func secretDataSize(data map[string][]byte) int {
+	n := 0
+	for k, v := range data {
+		n += len(k) + len(v)
+	}
+	return n
+}
@@ -0,0 +1,178 @@
+package kube
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/Azure/draft/pkg/storage"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestBuildsToPruneMaxCount(t *testing.T) {
+	now := time.Now()
+	builds := []*storage.Object{
+		{BuildID: "oldest", CreatedAt: now.Add(-3 * time.Hour)},
+		{BuildID: "middle", CreatedAt: now.Add(-2 * time.Hour)},
+		{BuildID: "newest", CreatedAt: now.Add(-1 * time.Hour)},
+	}
+	losers := buildsToPrune(builds, storage.RetentionPolicy{MaxCount: 2})
+	if len(losers) != 1 || losers[0] != "oldest" {
+		t.Fatalf("expected only %q pruned, got %v", "oldest", losers)
+	}
+}
+
+func TestBuildsToPruneMaxAge(t *testing.T) {
+	now := time.Now()
+	builds := []*storage.Object{
+		{BuildID: "stale", CreatedAt: now.Add(-48 * time.Hour)},
+		{BuildID: "fresh", CreatedAt: now.Add(-1 * time.Hour)},
+	}
+	losers := buildsToPrune(builds, storage.RetentionPolicy{MaxAge: 24 * time.Hour})
+	if len(losers) != 1 || losers[0] != "stale" {
+		t.Fatalf("expected only %q pruned, got %v", "stale", losers)
+	}
+}
+
+func TestBuildsToPruneKeepSuccessful(t *testing.T) {
+	now := time.Now()
+	builds := []*storage.Object{
+		{BuildID: "ok", CreatedAt: now.Add(-3 * time.Hour), Success: true},
+		{BuildID: "bad", CreatedAt: now.Add(-2 * time.Hour), Success: false},
+	}
+	losers := buildsToPrune(builds, storage.RetentionPolicy{MaxCount: 0, MaxAge: time.Hour, KeepSuccessful: true})
+	if len(losers) != 1 || losers[0] != "bad" {
+		t.Fatalf("expected only %q pruned, got %v", "bad", losers)
+	}
+}
+
+func TestConfigMapsStorePruneBuilds(t *testing.T) {
+	testStorePruneBuilds(t, NewConfigMapsStore(fake.NewSimpleClientset(), "default"))
+}
+
+func TestSecretsStorePruneBuilds(t *testing.T) {
+	testStorePruneBuilds(t, NewSecretsStore(fake.NewSimpleClientset(), "default"))
+}
+
+func testStorePruneBuilds(t *testing.T, store storage.Store) {
+	ctx := context.Background()
+	now := time.Now()
+	for i, age := range []time.Duration{3 * time.Hour, 2 * time.Hour, 1 * time.Hour} {
+		build := &storage.Object{
+			BuildID:   []string{"build-1", "build-2", "build-3"}[i],
+			AppName:   "myapp",
+			CreatedAt: now.Add(-age),
+			Data:      []byte("data"),
+		}
+		if err := store.CreateBuild(ctx, "myapp", build); err != nil {
+			t.Fatalf("unexpected error creating %s: %v", build.BuildID, err)
+		}
+	}
+
+	pruned, err := store.PruneBuilds(ctx, "myapp", storage.RetentionPolicy{MaxCount: 2})
+	if err != nil {
+		t.Fatalf("unexpected error pruning: %v", err)
+	}
+	if len(pruned) != 1 || pruned[0].BuildID != "build-1" {
+		t.Fatalf("expected only build-1 pruned, got %v", pruned)
+	}
+
+	builds, err := store.GetBuilds(ctx, "myapp")
+	if err != nil {
+		t.Fatalf("unexpected error listing builds: %v", err)
+	}
+	if len(builds) != 2 {
+		t.Fatalf("expected 2 remaining builds, got %d", len(builds))
+	}
+}
+
+func TestConfigMapsStoreCreateBuildPrunesOverSoftCap(t *testing.T) {
+	store := NewConfigMapsStore(fake.NewSimpleClientset(), "default", WithSoftCap(1024), WithPrunePolicy(storage.RetentionPolicy{MaxCount: 1}))
+	testStoreCreateBuildPrunesOverSoftCap(t, store)
+}
+
+func TestSecretsStoreCreateBuildPrunesOverSoftCap(t *testing.T) {
+	store := NewSecretsStore(fake.NewSimpleClientset(), "default", WithSoftCap(1024), WithPrunePolicy(storage.RetentionPolicy{MaxCount: 1}))
+	testStoreCreateBuildPrunesOverSoftCap(t, store)
+}
+
+func TestConfigMapsStoreCreateBuildEnforcesSoftCapUnderDefaultPolicy(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	store := NewConfigMapsStore(client, "default")
+	testStoreEnforcesSoftCapUnderDefaultPolicy(t, store, func() int {
+		cm, err := client.CoreV1().ConfigMaps("default").Get("myapp", metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("unexpected error fetching configmap: %v", err)
+		}
+		return configMapDataSize(cm.Data)
+	})
+}
+
+func TestSecretsStoreCreateBuildEnforcesSoftCapUnderDefaultPolicy(t *testing.T) {
+	client := fake.NewSimpleClientset()
+	store := NewSecretsStore(client, "default")
+	testStoreEnforcesSoftCapUnderDefaultPolicy(t, store, func() int {
+		secret, err := client.CoreV1().Secrets("default").Get("myapp", metav1.GetOptions{})
+		if err != nil {
+			t.Fatalf("unexpected error fetching secret: %v", err)
+		}
+		return secretDataSize(secret.Data)
+	})
+}
+
+// testStoreEnforcesSoftCapUnderDefaultPolicy creates several near-
+// maxInlineSize (unchunked) builds under the store's *default* soft cap and
+// prune policy (MaxCount: 50) -- well before the count-based policy would
+// ever select anything for removal on its own -- to prove the soft cap
+// holds regardless.
+func testStoreEnforcesSoftCapUnderDefaultPolicy(t *testing.T, store storage.Store, size func() int) {
+	ctx := context.Background()
+	now := time.Now()
+	for i := 0; i < 6; i++ {
+		build := &storage.Object{
+			BuildID:   fmt.Sprintf("build-%d", i),
+			AppName:   "myapp",
+			CreatedAt: now.Add(time.Duration(i) * time.Minute),
+			Data:      make([]byte, 120*1024),
+		}
+		if err := store.CreateBuild(ctx, "myapp", build); err != nil {
+			t.Fatalf("unexpected error creating %s: %v", build.BuildID, err)
+		}
+	}
+
+	if got := size(); got > defaultSoftCapBytes {
+		t.Fatalf("aggregate object is %d bytes, over the %d byte default soft cap", got, defaultSoftCapBytes)
+	}
+
+	builds, err := store.GetBuilds(ctx, "myapp")
+	if err != nil {
+		t.Fatalf("unexpected error listing builds: %v", err)
+	}
+	if len(builds) >= 6 {
+		t.Fatalf("expected older builds to be pruned to stay under the soft cap, got %d remaining", len(builds))
+	}
+}
+
+func testStoreCreateBuildPrunesOverSoftCap(t *testing.T, store storage.Store) {
+	ctx := context.Background()
+	now := time.Now()
+	old := &storage.Object{BuildID: "old", AppName: "myapp", CreatedAt: now.Add(-time.Hour), Data: make([]byte, 600)}
+	if err := store.CreateBuild(ctx, "myapp", old); err != nil {
+		t.Fatalf("unexpected error creating old build: %v", err)
+	}
+
+	next := &storage.Object{BuildID: "next", AppName: "myapp", CreatedAt: now, Data: make([]byte, 600)}
+	if err := store.CreateBuild(ctx, "myapp", next); err != nil {
+		t.Fatalf("unexpected error creating next build: %v", err)
+	}
+
+	builds, err := store.GetBuilds(ctx, "myapp")
+	if err != nil {
+		t.Fatalf("unexpected error listing builds: %v", err)
+	}
+	if len(builds) != 1 || builds[0].BuildID != "next" {
+		t.Fatalf("expected only %q to remain after soft cap pruning, got %v", "next", builds)
+	}
+}
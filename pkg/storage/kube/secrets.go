@@ -0,0 +1,259 @@
+package kube
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Azure/draft/pkg/storage"
+	apierrors "k8s.io/apimachinery/pkg/api/errors"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	k8s "k8s.io/client-go/kubernetes"
+	"k8s.io/client-go/pkg/api/v1"
+)
+
+// SecretsDriverName is the driver name used to select this backend with
+// NewStore.
+const SecretsDriverName = "secrets"
+
+// SecretsStore represents a Kubernetes Secret storage engine for a
+// storage.Object, with one aggregate Secret per application and, for
+// oversized builds, one additional Secret per chunk. Unlike ConfigMapsStore,
+// build payloads are never stored in plaintext.
+type SecretsStore struct {
+	client    k8s.Interface
+	namespace string
+	opts      storeOpts
+}
+
+var _ storage.Store = (*SecretsStore)(nil)
+var _ chunkBackend = (*SecretsStore)(nil)
+
+// NewSecretsStore returns a Store that persists builds as Secrets.
+func NewSecretsStore(c k8s.Interface, namespace string, opts ...StoreOpt) *SecretsStore {
+	s := &SecretsStore{client: c, namespace: namespace, opts: defaultStoreOpts()}
+	for _, opt := range opts {
+		opt(&s.opts)
+	}
+	return s
+}
+
+func buildLabels(appName string) map[string]string {
+	return map[string]string{"heritage": "draft", "appname": appName}
+}
+
+// DeleteBuilds deletes all draft builds for the application specified by appName.
+func (s *SecretsStore) DeleteBuilds(ctx context.Context, appName string) ([]*storage.Object, error) {
+	secret, err := s.client.CoreV1().Secrets(s.namespace).Get(appName, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	builds, err := s.decodeAll(appName, secret)
+	if err != nil {
+		return nil, err
+	}
+	for buildID, value := range secret.Data {
+		if err := deleteChunks(s, appName, buildID, string(value)); err != nil {
+			return nil, err
+		}
+	}
+	err = s.client.CoreV1().Secrets(s.namespace).Delete(appName, &metav1.DeleteOptions{})
+	return builds, err
+}
+
+// DeleteBuild deletes the draft build given by buildID for the application specified by appName.
+func (s *SecretsStore) DeleteBuild(ctx context.Context, appName, buildID string) (obj *storage.Object, err error) {
+	secret, err := s.client.CoreV1().Secrets(s.namespace).Get(appName, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	value, ok := secret.Data[buildID]
+	if !ok {
+		return nil, fmt.Errorf("application %q storage object %q not found", appName, buildID)
+	}
+	if obj, err = decodeValue(s, appName, buildID, string(value)); err != nil {
+		return nil, err
+	}
+	if err := deleteChunks(s, appName, buildID, string(value)); err != nil {
+		return nil, err
+	}
+	delete(secret.Data, buildID)
+	_, err = s.client.CoreV1().Secrets(s.namespace).Update(secret)
+	return obj, err
+}
+
+// CreateBuild stores a draft.Build for the application specified by appName.
+// Oversized payloads are split across chunk Secrets rather than stored
+// inline. An existing aggregate Secret is updated in place; only a new
+// application creates one. If adding build would push the aggregate Secret
+// over its soft cap, older builds are pruned first per the Store's
+// RetentionPolicy.
+func (s *SecretsStore) CreateBuild(ctx context.Context, appName string, build *storage.Object) error {
+	value, err := encodeValue(s, appName, build)
+	if err != nil {
+		return err
+	}
+
+	secret, err := s.client.CoreV1().Secrets(s.namespace).Get(appName, metav1.GetOptions{})
+	isNew := apierrors.IsNotFound(err)
+	if err != nil && !isNew {
+		return err
+	}
+	if isNew {
+		secret = &v1.Secret{
+			ObjectMeta: metav1.ObjectMeta{
+				Name:   appName,
+				Labels: buildLabels(appName),
+			},
+			Data: map[string][]byte{},
+		}
+	}
+	if secret.Data == nil {
+		secret.Data = map[string][]byte{}
+	}
+	secret.Data[build.BuildID] = []byte(value)
+
+	if secretDataSize(secret.Data) > s.opts.softCapBytes {
+		if err := s.pruneSecret(appName, secret); err != nil {
+			return err
+		}
+	}
+
+	if isNew {
+		_, err = s.client.CoreV1().Secrets(s.namespace).Create(secret)
+	} else {
+		_, err = s.client.CoreV1().Secrets(s.namespace).Update(secret)
+	}
+	return err
+}
+
+// PruneBuilds deletes the builds for appName that policy selects for
+// removal.
+func (s *SecretsStore) PruneBuilds(ctx context.Context, appName string, policy storage.RetentionPolicy) ([]*storage.Object, error) {
+	secret, err := s.client.CoreV1().Secrets(s.namespace).Get(appName, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	builds, err := s.decodeAll(appName, secret)
+	if err != nil {
+		return nil, err
+	}
+	pruned, err := s.removeBuilds(appName, secret, builds, buildsToPrune(builds, policy))
+	if err != nil || len(pruned) == 0 {
+		return pruned, err
+	}
+	_, err = s.client.CoreV1().Secrets(s.namespace).Update(secret)
+	return pruned, err
+}
+
+// pruneSecret prunes secret in place per the Store's configured
+// RetentionPolicy, without persisting the change; the caller is expected to
+// Create/Update secret itself. If the policy alone doesn't bring
+// secret.Data back under softCapBytes, additional oldest-first builds are
+// pruned until it does, so a generous MaxCount/MaxAge cannot keep it over
+// the soft cap. Builds protected by the policy's KeepSuccessful/KeepFailed
+// are never pruned, so secret.Data can stay over softCapBytes if only those
+// remain.
+func (s *SecretsStore) pruneSecret(appName string, secret *v1.Secret) error {
+	builds, err := s.decodeAll(appName, secret)
+	if err != nil {
+		return err
+	}
+	removed, err := s.removeBuilds(appName, secret, builds, buildsToPrune(builds, s.opts.prunePolicy))
+	if err != nil {
+		return err
+	}
+	if s.opts.softCapBytes <= 0 {
+		return nil
+	}
+	return pruneForCap(remainingBuilds(builds, removed), s.opts.prunePolicy, func() bool {
+		return secretDataSize(secret.Data) <= s.opts.softCapBytes
+	}, func(b *storage.Object) error {
+		if err := deleteChunks(s, appName, b.BuildID, string(secret.Data[b.BuildID])); err != nil {
+			return err
+		}
+		delete(secret.Data, b.BuildID)
+		return nil
+	})
+}
+
+// removeBuilds deletes the chunk resources for, and removes from
+// secret.Data, every build in builds whose BuildID is in losers.
+func (s *SecretsStore) removeBuilds(appName string, secret *v1.Secret, builds []*storage.Object, losers []string) ([]*storage.Object, error) {
+	if len(losers) == 0 {
+		return nil, nil
+	}
+	loserSet := make(map[string]bool, len(losers))
+	for _, id := range losers {
+		loserSet[id] = true
+	}
+	var removed []*storage.Object
+	for _, b := range builds {
+		if !loserSet[b.BuildID] {
+			continue
+		}
+		if err := deleteChunks(s, appName, b.BuildID, string(secret.Data[b.BuildID])); err != nil {
+			return nil, err
+		}
+		delete(secret.Data, b.BuildID)
+		removed = append(removed, b)
+	}
+	return removed, nil
+}
+
+// GetBuilds returns a slice of builds for the given app name.
+func (s *SecretsStore) GetBuilds(ctx context.Context, appName string) ([]*storage.Object, error) {
+	secret, err := s.client.CoreV1().Secrets(s.namespace).Get(appName, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	return s.decodeAll(appName, secret)
+}
+
+// GetBuild returns the build associated with buildID for the specified app name.
+func (s *SecretsStore) GetBuild(ctx context.Context, appName, buildID string) (*storage.Object, error) {
+	secret, err := s.client.CoreV1().Secrets(s.namespace).Get(appName, metav1.GetOptions{})
+	if err != nil {
+		return nil, err
+	}
+	value, ok := secret.Data[buildID]
+	if !ok {
+		return nil, fmt.Errorf("application %q storage object %q not found", appName, buildID)
+	}
+	return decodeValue(s, appName, buildID, string(value))
+}
+
+func (s *SecretsStore) decodeAll(appName string, secret *v1.Secret) ([]*storage.Object, error) {
+	var builds []*storage.Object
+	for buildID, value := range secret.Data {
+		build, err := decodeValue(s, appName, buildID, string(value))
+		if err != nil {
+			return nil, err
+		}
+		builds = append(builds, build)
+	}
+	return builds, nil
+}
+
+// createChunk implements chunkBackend.
+func (s *SecretsStore) createChunk(name string, labels map[string]string, content string) error {
+	secret := &v1.Secret{
+		ObjectMeta: metav1.ObjectMeta{Name: name, Labels: labels},
+		Data:       map[string][]byte{"chunk": []byte(content)},
+	}
+	_, err := s.client.CoreV1().Secrets(s.namespace).Create(secret)
+	return err
+}
+
+// getChunk implements chunkBackend.
+func (s *SecretsStore) getChunk(name string) (string, error) {
+	secret, err := s.client.CoreV1().Secrets(s.namespace).Get(name, metav1.GetOptions{})
+	if err != nil {
+		return "", err
+	}
+	return string(secret.Data["chunk"]), nil
+}
+
+// deleteChunk implements chunkBackend.
+func (s *SecretsStore) deleteChunk(name string) error {
+	return s.client.CoreV1().Secrets(s.namespace).Delete(name, &metav1.DeleteOptions{})
+}
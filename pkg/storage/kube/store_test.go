@@ -0,0 +1,104 @@
+package kube
+
+import (
+	"context"
+	"testing"
+
+	"github.com/Azure/draft/pkg/storage"
+	"k8s.io/client-go/kubernetes/fake"
+)
+
+func TestNewStoreUnknownDriver(t *testing.T) {
+	if _, err := NewStore("bogus", "default", fake.NewSimpleClientset()); err == nil {
+		t.Error("expected an error for an unknown driver, got nil")
+	}
+}
+
+func TestConfigMapsStoreCreateGetDeleteBuild(t *testing.T) {
+	store := NewConfigMapsStore(fake.NewSimpleClientset(), "default")
+	testStoreCreateGetDeleteBuild(t, store)
+}
+
+func TestSecretsStoreCreateGetDeleteBuild(t *testing.T) {
+	store := NewSecretsStore(fake.NewSimpleClientset(), "default")
+	testStoreCreateGetDeleteBuild(t, store)
+}
+
+func testStoreCreateGetDeleteBuild(t *testing.T, store storage.Store) {
+	ctx := context.Background()
+	build1 := &storage.Object{BuildID: "build-1", AppName: "myapp", Data: []byte("hello")}
+	if err := store.CreateBuild(ctx, "myapp", build1); err != nil {
+		t.Fatalf("unexpected error creating first build: %v", err)
+	}
+
+	// A second CreateBuild for the same app must not clobber the first.
+	build2 := &storage.Object{BuildID: "build-2", AppName: "myapp", Data: []byte("world")}
+	if err := store.CreateBuild(ctx, "myapp", build2); err != nil {
+		t.Fatalf("unexpected error creating second build: %v", err)
+	}
+
+	builds, err := store.GetBuilds(ctx, "myapp")
+	if err != nil {
+		t.Fatalf("unexpected error listing builds: %v", err)
+	}
+	if len(builds) != 2 {
+		t.Fatalf("expected 2 builds, got %d", len(builds))
+	}
+
+	got, err := store.GetBuild(ctx, "myapp", "build-1")
+	if err != nil {
+		t.Fatalf("unexpected error fetching build-1: %v", err)
+	}
+	if string(got.Data) != "hello" {
+		t.Errorf("expected build-1 data %q, got %q", "hello", got.Data)
+	}
+
+	if _, err := store.DeleteBuild(ctx, "myapp", "build-1"); err != nil {
+		t.Fatalf("unexpected error deleting build-1: %v", err)
+	}
+	if _, err := store.GetBuild(ctx, "myapp", "build-1"); err == nil {
+		t.Error("expected an error fetching a deleted build, got nil")
+	}
+}
+
+func TestConfigMapsStoreChunksOversizedBuild(t *testing.T) {
+	store := NewConfigMapsStore(fake.NewSimpleClientset(), "default")
+	testStoreChunksOversizedBuild(t, store)
+}
+
+func TestSecretsStoreChunksOversizedBuild(t *testing.T) {
+	store := NewSecretsStore(fake.NewSimpleClientset(), "default")
+	testStoreChunksOversizedBuild(t, store)
+}
+
+func testStoreChunksOversizedBuild(t *testing.T, store storage.Store) {
+	ctx := context.Background()
+	big := make([]byte, maxInlineSize*3)
+	for i := range big {
+		big[i] = byte(i % 251)
+	}
+	build := &storage.Object{BuildID: "big-build", AppName: "myapp", Data: big}
+	if err := store.CreateBuild(ctx, "myapp", build); err != nil {
+		t.Fatalf("unexpected error creating oversized build: %v", err)
+	}
+
+	got, err := store.GetBuild(ctx, "myapp", "big-build")
+	if err != nil {
+		t.Fatalf("unexpected error fetching oversized build: %v", err)
+	}
+	if len(got.Data) != len(big) {
+		t.Fatalf("expected reassembled data of length %d, got %d", len(big), len(got.Data))
+	}
+	for i := range big {
+		if got.Data[i] != big[i] {
+			t.Fatalf("reassembled data diverges at byte %d", i)
+		}
+	}
+
+	if _, err := store.DeleteBuild(ctx, "myapp", "big-build"); err != nil {
+		t.Fatalf("unexpected error deleting oversized build: %v", err)
+	}
+	if _, err := store.GetBuild(ctx, "myapp", "big-build"); err == nil {
+		t.Error("expected an error fetching a deleted build, got nil")
+	}
+}
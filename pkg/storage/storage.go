@@ -0,0 +1,83 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"encoding/base64"
+	"encoding/gob"
+	"fmt"
+	"time"
+)
+
+// Object represents a single recorded draft build.
+type Object struct {
+	// BuildID uniquely identifies this build within an application.
+	BuildID string
+	// AppName is the application this build belongs to.
+	AppName string
+	// CreatedAt is when the build was recorded, used to order and age out
+	// builds under a RetentionPolicy.
+	CreatedAt time.Time
+	// Success reports whether the build completed successfully, used to
+	// select builds under a RetentionPolicy's KeepSuccessful/KeepFailed.
+	Success bool
+	// Data holds the build's recorded payload (e.g. logs, manifests).
+	Data []byte
+}
+
+// RetentionPolicy controls which builds PruneBuilds is allowed to delete.
+// A zero MaxCount or MaxAge means that selector is not applied.
+type RetentionPolicy struct {
+	// MaxCount keeps at most the MaxCount most recently created builds.
+	MaxCount int
+	// MaxAge deletes builds created more than MaxAge ago.
+	MaxAge time.Duration
+	// KeepSuccessful, if true, never deletes a build recorded as successful,
+	// regardless of MaxCount/MaxAge.
+	KeepSuccessful bool
+	// KeepFailed, if true, never deletes a build recorded as failed,
+	// regardless of MaxCount/MaxAge.
+	KeepFailed bool
+}
+
+// Store is the interface that wraps the persistence operations draftd needs
+// to record and retrieve builds. Implementations are free to choose how and
+// where builds are stored, so long as they round-trip Objects faithfully.
+type Store interface {
+	// CreateBuild records build for the application specified by appName.
+	CreateBuild(ctx context.Context, appName string, build *Object) error
+	// GetBuild returns the build associated with buildID for appName.
+	GetBuild(ctx context.Context, appName, buildID string) (*Object, error)
+	// GetBuilds returns every recorded build for appName.
+	GetBuilds(ctx context.Context, appName string) ([]*Object, error)
+	// DeleteBuild deletes the build given by buildID for appName.
+	DeleteBuild(ctx context.Context, appName, buildID string) (*Object, error)
+	// DeleteBuilds deletes every recorded build for appName.
+	DeleteBuilds(ctx context.Context, appName string) ([]*Object, error)
+	// PruneBuilds deletes the builds for appName that policy selects for
+	// removal, returning the deleted builds.
+	PruneBuilds(ctx context.Context, appName string, policy RetentionPolicy) ([]*Object, error)
+}
+
+// EncodeToString gob-encodes obj and returns it as a base64 string, suitable
+// for storing as a single ConfigMap/Secret data value.
+func EncodeToString(obj *Object) (string, error) {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(obj); err != nil {
+		return "", fmt.Errorf("failed to encode storage object: %v", err)
+	}
+	return base64.StdEncoding.EncodeToString(buf.Bytes()), nil
+}
+
+// DecodeString reverses EncodeToString.
+func DecodeString(data string) (*Object, error) {
+	raw, err := base64.StdEncoding.DecodeString(data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to base64-decode storage object: %v", err)
+	}
+	var obj Object
+	if err := gob.NewDecoder(bytes.NewReader(raw)).Decode(&obj); err != nil {
+		return nil, fmt.Errorf("failed to decode storage object: %v", err)
+	}
+	return &obj, nil
+}